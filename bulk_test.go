@@ -0,0 +1,106 @@
+package excelize
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetSheetBulkUnsafe(t *testing.T) {
+	f := NewFile()
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	block := [][]any{
+		{"Acme", 42, 3.5, true, "=1+1", nil},
+		{"Globex", int64(7), float32(1.25), false, now, nil},
+	}
+	assert.NoError(t, f.SetSheetBulkUnsafe("Sheet1", "A1", block))
+
+	v, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme", v)
+
+	v, err = f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", v)
+
+	v, err = f.GetCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.5", v)
+
+	formula, err := f.GetCellFormula("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1+1", formula)
+
+	v, err = f.GetCellValue("Sheet1", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", v)
+}
+
+func TestSetSheetBulkUnsafeFormulaOverwritesValue(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 99))
+	assert.NoError(t, f.SetSheetBulkUnsafe("Sheet1", "A1", [][]any{{"=1+1"}}))
+
+	formula, err := f.GetCellFormula("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1+1", formula)
+
+	v, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "99", v)
+}
+
+func TestSetSheetBulkUnsafeColStylesAppliesAcrossTypes(t *testing.T) {
+	f := NewFile()
+	styleID, err := f.NewStyle(&Style{NumFmt: 1})
+	assert.NoError(t, err)
+
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	block := [][]any{
+		{now},
+		{"=1+1"},
+		{42},
+		{nil},
+	}
+	assert.NoError(t, f.SetSheetBulkUnsafe("Sheet1", "A1", block, BulkOptions{ColStyles: []int{styleID}}))
+
+	for _, cell := range []string{"A1", "A2", "A3", "A4"} {
+		got, serr := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, serr)
+		assert.Equal(t, styleID, got, "cell %s", cell)
+	}
+}
+
+func TestSetSheetBulkUnsafeDedupSharedStrings(t *testing.T) {
+	f := NewFile()
+	block := [][]any{{"shared", "shared"}}
+	assert.NoError(t, f.SetSheetBulkUnsafe("Sheet1", "A1", block, BulkOptions{DedupSharedStrings: true}))
+
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "s", ws.SheetData.Row[0].C[0].T)
+	assert.Equal(t, ws.SheetData.Row[0].C[0].V, ws.SheetData.Row[0].C[1].V)
+
+	v, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "shared", v)
+
+	v, err = f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "shared", v)
+}
+
+func TestSetSheetBulkUnsafeRemoveFormulas(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "=1+1"))
+	assert.NoError(t, f.SetSheetBulkUnsafe("Sheet1", "A1", [][]any{{"plain"}}, BulkOptions{RemoveFormulas: true}))
+
+	formula, err := f.GetCellFormula("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", formula)
+
+	v, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", v)
+}
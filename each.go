@@ -3,6 +3,8 @@ package excelize
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/xuri/efp"
 )
@@ -68,7 +70,12 @@ func (f *File) EachCellFormula(sheet string, fn func(cell, formula string) bool)
 }
 
 // EachCellFormulaValue provides a function to get formula and value from cell by given
-// worksheet name and cell reference in spreadsheet.
+// worksheet name and cell reference in spreadsheet. When the workbook's
+// formula cache is enabled (see EnableFormulaCache), each cell visited here
+// also warms that cache, so a later EachCellFormulaValue or
+// EachCellFormulaValueInRange pass takes that coordinate's formula and value
+// from the cache instead of re-reading and re-resolving them. GetCellFormula
+// and GetCellValue do not consult the cache.
 func (f *File) EachCellFormulaValue(sheet string, fn func(cell, formula, value string) bool) error {
 	sharedFormulaCache := make(map[int]string)
 	colCache := make(map[int]int)
@@ -77,35 +84,281 @@ func (f *File) EachCellFormulaValue(sheet string, fn func(cell, formula, value s
 	if err != nil {
 		return err
 	}
+	cache := f.formulaCache()
 
 	return f.eachCellStringFunc(sheet, func(x *xlsxWorksheet, c *xlsxC) (bool, error) {
+		if cache != nil {
+			if col, row, cerr := CellNameToCoordinates(c.R); cerr == nil {
+				if entry, ok := cache.get(sheet, col, row); ok {
+					// Still record a shared-formula origin, so a dependent
+					// cell that isn't cached resolves against it.
+					if c.F != nil && c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+						if _, seen := sharedFormulaCache[*c.F.Si]; !seen {
+							sharedFormulaCache[*c.F.Si] = c.F.Content
+							colCache[*c.F.Si], rowCache[*c.F.Si] = col, row
+						}
+					}
+					return fn(c.R, entry.formula, entry.value), nil
+				}
+			}
+		}
+
 		val, err := c.getValueFrom(f, sst, true)
 		if err != nil {
 			return false, err
 		}
 
-		if c.F == nil {
-			return fn(c.R, "", val), nil
+		formula := ""
+		if c.F != nil {
+			formula = c.F.Content
+			if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+				sfc, cached := sharedFormulaCache[*c.F.Si]
+				if !cached {
+					sharedFormulaCache[*c.F.Si] = c.F.Content
+					colCache[*c.F.Si], rowCache[*c.F.Si], _ = CellNameToCoordinates(c.R)
+				} else {
+					col, row, _ := CellNameToCoordinates(c.R)
+					dCol := col - colCache[*c.F.Si]
+					dRow := row - rowCache[*c.F.Si]
+					orig := []byte(sfc)
+					res, start := parseSharedFormula(dCol, dRow, orig)
+					if start < len(orig) {
+						res += string(orig[start:])
+					}
+					formula = res
+				}
+			}
 		}
-		if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
-			sfc, cached := sharedFormulaCache[*c.F.Si]
-			if !cached {
+
+		if cache != nil {
+			if col, row, cerr := CellNameToCoordinates(c.R); cerr == nil {
+				cache.put(sheet, col, row, formulaValueEntry{formula: formula, value: val})
+			}
+		}
+		return fn(c.R, formula, val), nil
+	})
+}
+
+// parseCellRangeRef resolves a range reference (e.g. "A1:D50", "$A$1:$C$3",
+// the whole-column form "A:C" or the whole-row form "1:3") into 1-based
+// start/end column and row bounds. Whole-column and whole-row forms are
+// bounded by the sheet's current dimension. Cross-sheet references such as
+// "Sheet2!A1:C3" are rejected.
+func (f *File) parseCellRangeRef(sheet, rangeRef string) (startCol, startRow, endCol, endRow int, err error) {
+	if strings.Contains(rangeRef, "!") {
+		return 0, 0, 0, 0, fmt.Errorf("cross-sheet range references are not supported: %q", rangeRef)
+	}
+	parts := strings.Split(strings.ReplaceAll(rangeRef, "$", ""), ":")
+	if len(parts) != 2 {
+		return 0, 0, 0, 0, fmt.Errorf("invalid range reference %q", rangeRef)
+	}
+	start, end := parts[0], parts[1]
+
+	if isDigitsOnly(start) && isDigitsOnly(end) {
+		if startRow, err = strconv.Atoi(start); err != nil {
+			return 0, 0, 0, 0, err
+		}
+		if endRow, err = strconv.Atoi(end); err != nil {
+			return 0, 0, 0, 0, err
+		}
+		_, startCol, endCol, err = f.sheetBounds(sheet)
+		startCol = 1
+		return validateCellRangeRef(rangeRef, startCol, startRow, endCol, endRow, err)
+	}
+	if isAlphaOnly(start) && isAlphaOnly(end) {
+		if startCol, err = ColumnNameToNumber(start); err != nil {
+			return 0, 0, 0, 0, err
+		}
+		if endCol, err = ColumnNameToNumber(end); err != nil {
+			return 0, 0, 0, 0, err
+		}
+		endRow, _, _, err = f.sheetBounds(sheet)
+		startRow = 1
+		return validateCellRangeRef(rangeRef, startCol, startRow, endCol, endRow, err)
+	}
+	if startCol, startRow, err = CellNameToCoordinates(start); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	if endCol, endRow, err = CellNameToCoordinates(end); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return validateCellRangeRef(rangeRef, startCol, startRow, endCol, endRow, nil)
+}
+
+// validateCellRangeRef rejects a reversed range - startCol > endCol or
+// startRow > endRow - with a normal error instead of letting it through to
+// a caller that sizes a slice/make() from endCol-startCol+1 or
+// endRow-startRow+1, where a negative result panics.
+func validateCellRangeRef(rangeRef string, startCol, startRow, endCol, endRow int, err error) (int, int, int, int, error) {
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	if startCol > endCol || startRow > endRow {
+		return 0, 0, 0, 0, fmt.Errorf("invalid range reference %q: start must not be after end", rangeRef)
+	}
+	return startCol, startRow, endCol, endRow, nil
+}
+
+// sheetBounds returns the sheet's current dimension as (maxRow, minCol,
+// maxCol), used to bound whole-column and whole-row range references. A
+// single-cell dimension such as "A1" bounds the sheet to that one cell.
+func (f *File) sheetBounds(sheet string) (maxRow, minCol, maxCol int, err error) {
+	dimension, err := f.GetSheetDimension(sheet)
+	if err != nil || dimension == "" {
+		return 0, 0, 0, err
+	}
+	parts := strings.Split(dimension, ":")
+	if len(parts) == 1 {
+		parts = append(parts, parts[0])
+	}
+	if len(parts) != 2 {
+		return 0, 0, 0, fmt.Errorf("invalid sheet dimension %q", dimension)
+	}
+	if minCol, _, err = CellNameToCoordinates(parts[0]); err != nil {
+		return 0, 0, 0, err
+	}
+	if maxCol, maxRow, err = CellNameToCoordinates(parts[1]); err != nil {
+		return 0, 0, 0, err
+	}
+	return maxRow, minCol, maxCol, nil
+}
+
+func isDigitsOnly(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphaOnly(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// EachCellFormulaValueInRange works like EachCellFormulaValue but restricts
+// the walk itself to rangeRef, padding any cell that has no data with an
+// empty formula and value so callers always see a rectangular result. Only
+// cells inside the range have their values read and their formulas
+// resolved. Every row of ws.SheetData.Row is still scanned once, reading
+// only each cell's formula, to index the rows inside the range and to find
+// shared-formula origins that may lie outside it.
+func (f *File) EachCellFormulaValueInRange(sheet, rangeRef string, fn func(cell, formula, value string) bool) error {
+	startCol, startRow, endCol, endRow, err := f.parseCellRangeRef(sheet, rangeRef)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return err
+	}
+	cache := f.formulaCache()
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	// Learn every shared-formula origin on the sheet first. This pass only
+	// reads c.F, never c.getValueFrom, so it stays cheap even on a huge
+	// sheet and lets a shared-formula cell inside rangeRef resolve
+	// correctly even when its origin lies outside the range.
+	sharedFormulaCache := make(map[int]string)
+	colCache := make(map[int]int)
+	rowCache := make(map[int]int)
+	rowIndex := make(map[int]*xlsxRow, endRow-startRow+1)
+	for rowIdx := range ws.SheetData.Row {
+		rowData := &ws.SheetData.Row[rowIdx]
+		if rowData.R >= startRow && rowData.R <= endRow {
+			rowIndex[rowData.R] = rowData
+		}
+		for colIdx := range rowData.C {
+			c := &rowData.C[colIdx]
+			if c.F == nil || c.F.T != STCellFormulaTypeShared || c.F.Si == nil {
+				continue
+			}
+			if _, ok := sharedFormulaCache[*c.F.Si]; !ok {
 				sharedFormulaCache[*c.F.Si] = c.F.Content
 				colCache[*c.F.Si], rowCache[*c.F.Si], _ = CellNameToCoordinates(c.R)
-			} else {
-				col, row, _ := CellNameToCoordinates(c.R)
-				dCol := col - colCache[*c.F.Si]
-				dRow := row - rowCache[*c.F.Si]
-				orig := []byte(sfc)
-				res, start := parseSharedFormula(dCol, dRow, orig)
-				if start < len(orig) {
-					res += string(orig[start:])
+			}
+		}
+	}
+
+	for row := startRow; row <= endRow; row++ {
+		var cellIndex map[int]*xlsxC
+		if rowData, ok := rowIndex[row]; ok {
+			cellIndex = make(map[int]*xlsxC, len(rowData.C))
+			for colIdx := range rowData.C {
+				c := &rowData.C[colIdx]
+				col, _, cerr := CellNameToCoordinates(c.R)
+				if cerr != nil || col < startCol || col > endCol {
+					continue
 				}
-				return fn(c.R, res, val), nil
+				cellIndex[col] = c
 			}
 		}
-		return fn(c.R, c.F.Content, val), nil
-	})
+		for col := startCol; col <= endCol; col++ {
+			cellName, cerr := CoordinatesToCellName(col, row)
+			if cerr != nil {
+				return cerr
+			}
+			c, ok := cellIndex[col]
+			if !ok {
+				if fn(cellName, "", "") {
+					return nil
+				}
+				continue
+			}
+			if cache != nil {
+				if entry, cached := cache.get(sheet, col, row); cached {
+					if fn(cellName, entry.formula, entry.value) {
+						return nil
+					}
+					continue
+				}
+			}
+			val, verr := c.getValueFrom(f, sst, true)
+			if verr != nil {
+				return verr
+			}
+			formula := ""
+			if c.F != nil {
+				formula = c.F.Content
+				if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+					if sfc, cached := sharedFormulaCache[*c.F.Si]; cached {
+						dCol := col - colCache[*c.F.Si]
+						dRow := row - rowCache[*c.F.Si]
+						orig := []byte(sfc)
+						res, start := parseSharedFormula(dCol, dRow, orig)
+						if start < len(orig) {
+							res += string(orig[start:])
+						}
+						formula = res
+					}
+				}
+			}
+			if cache != nil {
+				cache.put(sheet, col, row, formulaValueEntry{formula: formula, value: val})
+			}
+			if fn(cellName, formula, val) {
+				return nil
+			}
+		}
+	}
+	return nil
 }
 
 type IteratorCellValue struct {
@@ -167,6 +420,62 @@ func (f *File) IterateRowFormulaValues(sheet string) (Next func() []IteratorCell
 	return Next, Close
 }
 
+// IterateRangeFormulaValues works like IterateRowFormulaValues but walks only
+// rangeRef, yielding one padded, rectangular row at a time.
+func (f *File) IterateRangeFormulaValues(sheet, rangeRef string) (Next func() []IteratorCellValue, Close func()) {
+	startCol, _, endCol, _, parseErr := f.parseCellRangeRef(sheet, rangeRef)
+	if parseErr != nil {
+		panic(parseErr)
+	}
+	width := endCol - startCol + 1
+
+	rowChan := make(chan []IteratorCellValue)
+	closeChan := make(chan struct{})
+	doneChan := make(chan struct{})
+	go func() {
+		defer close(doneChan)
+		nextRow := make([]IteratorCellValue, 0, width)
+		closed := false
+		err := f.EachCellFormulaValueInRange(sheet, rangeRef, func(cell, formula, value string) bool {
+			nextRow = append(nextRow, IteratorCellValue{Formula: formula, Value: value})
+			if len(nextRow) == width {
+				select {
+				case rowChan <- nextRow:
+					nextRow = make([]IteratorCellValue, 0, width)
+				case <-closeChan:
+					closed = true
+					return true
+				}
+			}
+			return false
+		})
+		if err != nil {
+			panic(err)
+		}
+		if len(nextRow) > 0 && !closed {
+			select {
+			case rowChan <- nextRow:
+			case <-closeChan:
+			}
+		}
+	}()
+	Next = func() []IteratorCellValue {
+		select {
+		case row := <-rowChan:
+			return row
+		case <-doneChan:
+		}
+		return nil
+	}
+	Close = func() {
+		select {
+		case closeChan <- struct{}{}:
+		case <-doneChan:
+		}
+	}
+	return Next, Close
+}
+
 func (f *File) SetRowCells(sheet string, row int, values ...string) error {
 	rowStr := strconv.Itoa(row)
 	for i, val := range values {
@@ -314,3 +623,185 @@ func (f *File) SetSheetBulkFloatUnsafe(sheet, cell string, block [][]*float64) e
 	}
 	return nil
 }
+
+// BulkOptions controls how SetSheetBulkUnsafe writes a block of cells.
+type BulkOptions struct {
+	// RemoveFormulas drops any existing formula on a cell before it is
+	// overwritten. It is opt-in because, like the other bulk setters,
+	// SetSheetBulkUnsafe otherwise skips the formula-removal bookkeeping
+	// for performance.
+	RemoveFormulas bool
+	// ColStyles gives a default style ID per column of block, indexed from
+	// 0. A zero entry (or a short slice) falls back to the cell's existing
+	// style, same as the other bulk setters.
+	ColStyles []int
+	// DedupSharedStrings routes string cells through the shared-string
+	// table via setCellString instead of writing inline "str" cells.
+	DedupSharedStrings bool
+}
+
+// SetSheetBulkUnsafe sets a block of typed values starting with given cell,
+// inferring the Excel cell type from each value's Go type: ints and floats
+// become numbers (floats formatted with strconv.FormatFloat's shortest
+// representation, not a fixed number of decimals), bool becomes a boolean
+// cell, time.Time becomes a date, a string starting with "=" becomes a
+// formula, a []RichTextRun is written through SetCellRichText as a rich
+// string in the shared-string table (not as an inline string), and nil
+// clears the cell. It does not remove any formulas for performance unless
+// BulkOptions.RemoveFormulas is set, so make sure there aren't any. If a
+// FormulaValueCache is enabled on f, every entry for sheet is dropped once
+// the block is written, since a bulk write can touch far more coordinates
+// than it's worth tracking individually.
+func (f *File) SetSheetBulkUnsafe(sheet, cell string, block [][]any, opts ...BulkOptions) error {
+	var opt BulkOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	startCol, startRow, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	f.mu.Unlock()
+
+	for i := 0; i < len(block); i++ {
+		slice := block[i]
+		for j := 0; j < len(slice); j++ {
+			value := slice[j]
+			col := startCol + j
+			row := startRow + i
+			cellRef, err := CoordinatesToCellName(col, row)
+			if err != nil {
+				return err
+			}
+
+			// time.Time and rich text need the full SetCell* machinery
+			// (number formats, shared-string rich runs), so they go
+			// through the public API instead of the fast inline path. A
+			// "="-prefixed string is a formula and goes through
+			// SetCellFormula too, so the cell's cached value (c.V) gets
+			// cleared instead of carrying over stale content from
+			// whatever the cell held before. All three bypass the
+			// ws.prepareCellStyle call below, so ColStyles is applied
+			// through SetCellStyle instead, to land the same column
+			// default regardless of which path a given row's value takes.
+			switch v := value.(type) {
+			case time.Time:
+				if err = f.SetCellValue(sheet, cellRef, v); err != nil {
+					return err
+				}
+				if err = applyBulkColStyle(f, sheet, cellRef, j, opt); err != nil {
+					return err
+				}
+				continue
+			case []RichTextRun:
+				if err = f.SetCellRichText(sheet, cellRef, v); err != nil {
+					return err
+				}
+				if err = applyBulkColStyle(f, sheet, cellRef, j, opt); err != nil {
+					return err
+				}
+				continue
+			case string:
+				if len(v) > 0 && v[0] == '=' {
+					if err = f.SetCellFormula(sheet, cellRef, v[1:]); err != nil {
+						return err
+					}
+					if err = applyBulkColStyle(f, sheet, cellRef, j, opt); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+
+			ws.mu.Lock()
+			ws.prepareSheetXML(col, row)
+			c := &ws.SheetData.Row[row-1].C[col-1]
+			if j < len(opt.ColStyles) && opt.ColStyles[j] != 0 {
+				c.S = opt.ColStyles[j]
+			} else {
+				c.S = ws.prepareCellStyle(col, row, c.S)
+			}
+			if opt.RemoveFormulas {
+				c.F = nil
+			}
+			err = setBulkCellFast(f, c, value, opt)
+			ws.mu.Unlock()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	f.invalidateFormulaCacheSheet(sheet)
+	return nil
+}
+
+// applyBulkColStyle applies opt.ColStyles[col] to cellRef via the public
+// SetCellStyle API, for the value types that SetSheetBulkUnsafe routes
+// through the public SetCell* API and so never reach the
+// ws.prepareCellStyle call that applies ColStyles on the fast path.
+func applyBulkColStyle(f *File, sheet, cellRef string, col int, opt BulkOptions) error {
+	if col >= len(opt.ColStyles) || opt.ColStyles[col] == 0 {
+		return nil
+	}
+	return f.SetCellStyle(sheet, cellRef, cellRef, opt.ColStyles[col])
+}
+
+// setBulkCellFast writes value into c without taking any additional locks,
+// for the value types cheap enough to not need the public SetCell* API.
+// Formula strings never reach here - SetSheetBulkUnsafe routes them through
+// SetCellFormula instead, same as time.Time and []RichTextRun.
+func setBulkCellFast(f *File, c *xlsxC, value any, opt BulkOptions) error {
+	switch v := value.(type) {
+	case nil:
+		c.T, c.V, c.IS = "", "", nil
+	case string:
+		var err error
+		if opt.DedupSharedStrings {
+			if c.T, c.V, err = f.setCellString(v); err != nil {
+				return err
+			}
+		} else {
+			c.T, c.V = "str", v
+		}
+		c.IS = nil
+	case bool:
+		c.T, c.V, c.IS = "b", "0", nil
+		if v {
+			c.V = "1"
+		}
+	case int:
+		c.T, c.V, c.IS = "", strconv.Itoa(v), nil
+	case int8:
+		c.T, c.V, c.IS = "", strconv.FormatInt(int64(v), 10), nil
+	case int16:
+		c.T, c.V, c.IS = "", strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		c.T, c.V, c.IS = "", strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		c.T, c.V, c.IS = "", strconv.FormatInt(v, 10), nil
+	case uint:
+		c.T, c.V, c.IS = "", strconv.FormatUint(uint64(v), 10), nil
+	case uint8:
+		c.T, c.V, c.IS = "", strconv.FormatUint(uint64(v), 10), nil
+	case uint16:
+		c.T, c.V, c.IS = "", strconv.FormatUint(uint64(v), 10), nil
+	case uint32:
+		c.T, c.V, c.IS = "", strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		c.T, c.V, c.IS = "", strconv.FormatUint(v, 10), nil
+	case float32:
+		c.T, c.V, c.IS = "", strconv.FormatFloat(float64(v), 'f', -1, 64), nil
+	case float64:
+		c.T, c.V, c.IS = "", strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return fmt.Errorf("SetSheetBulkUnsafe: unsupported cell value type %T", value)
+	}
+	return nil
+}
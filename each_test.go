@@ -0,0 +1,68 @@
+package excelize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEachCellFormulaValueInRange(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "keep out"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", "in range"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C2", "=1+1"))
+
+	var cells []string
+	var values []string
+	assert.NoError(t, f.EachCellFormulaValueInRange("Sheet1", "B2:C3", func(cell, formula, value string) bool {
+		cells = append(cells, cell)
+		values = append(values, value)
+		return false
+	}))
+	assert.Equal(t, []string{"B2", "C2", "B3", "C3"}, cells)
+	assert.Equal(t, []string{"in range", "2", "", ""}, values)
+
+	assert.Error(t, f.EachCellFormulaValueInRange("Sheet1", "Sheet2!A1:B2", func(cell, formula, value string) bool {
+		return false
+	}))
+
+	assert.Error(t, f.EachCellFormulaValueInRange("Sheet1", "C3:B2", func(cell, formula, value string) bool {
+		return false
+	}))
+}
+
+func TestIterateRangeFormulaValues(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C2", 2))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B3", 3))
+
+	Next, Close := f.IterateRangeFormulaValues("Sheet1", "B2:C3")
+	defer Close()
+
+	row := Next()
+	assert.Len(t, row, 2)
+	assert.Equal(t, "1", row[0].Value)
+	assert.Equal(t, "2", row[1].Value)
+
+	row = Next()
+	assert.Len(t, row, 2)
+	assert.Equal(t, "3", row[0].Value)
+	assert.Equal(t, "", row[1].Value)
+
+	assert.Nil(t, Next())
+}
+
+func TestIterateRangeFormulaValuesInvalidRange(t *testing.T) {
+	f := NewFile()
+	assert.Panics(t, func() {
+		f.IterateRangeFormulaValues("Sheet1", "Sheet2!A1:B2")
+	})
+}
+
+func TestIterateRangeFormulaValuesReversedRange(t *testing.T) {
+	f := NewFile()
+	assert.Panics(t, func() {
+		f.IterateRangeFormulaValues("Sheet1", "C3:B2")
+	})
+}
@@ -0,0 +1,125 @@
+package excelize
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/PaesslerAG/gval"
+)
+
+// Evaluator evaluates a non-Excel expression against an environment of
+// variables, returning a Go value that SetCellExpression converts to the
+// appropriate Excel cell type. The default implementation is backed by
+// gval; callers can plug in their own via SetEvaluator.
+type Evaluator interface {
+	Evaluate(expr string, env map[string]any) (any, error)
+}
+
+// gvalEvaluator is the default Evaluator, backed by gval.Full. It caches
+// parsed expressions so that repeated evaluation of the same expression
+// string - the common case for SetSheetExpressions - only parses once.
+type gvalEvaluator struct {
+	mu          sync.Mutex
+	expressions map[string]gval.Evaluable
+}
+
+func newGvalEvaluator() *gvalEvaluator {
+	return &gvalEvaluator{expressions: make(map[string]gval.Evaluable)}
+}
+
+// Evaluate implements the Evaluator interface.
+func (e *gvalEvaluator) Evaluate(expr string, env map[string]any) (any, error) {
+	e.mu.Lock()
+	evaluable, ok := e.expressions[expr]
+	if !ok {
+		var err error
+		if evaluable, err = gval.Full().NewEvaluable(expr); err != nil {
+			e.mu.Unlock()
+			return nil, err
+		}
+		e.expressions[expr] = evaluable
+	}
+	e.mu.Unlock()
+	return evaluable(context.Background(), env)
+}
+
+// evaluators holds the per-workbook Evaluator, keyed by *File. A side-table
+// avoids growing the File struct for a feature most callers never
+// customize, same as formulaValueCaches. An entry is added here by
+// SetEvaluator, or lazily by evaluator() on first use of
+// SetCellExpression/SetSheetExpressions - so a long-lived process that
+// creates many workbooks and uses this feature must call
+// SetEvaluator(nil) on each *File once done with it, or it is pinned in
+// this map (and kept alive) for the rest of the process's life.
+var evaluators sync.Map // map[*File]Evaluator
+
+// SetEvaluator overrides the Evaluator used by SetCellExpression and
+// SetSheetExpressions on f. Without a call to SetEvaluator, f uses a
+// gval-backed default.
+//
+// Calling SetEvaluator(nil) removes f's entry from the package-level
+// evaluator table instead of storing a nil Evaluator, reverting f to the
+// gval-backed default on its next expression call. A long-running process
+// that creates many *File values and calls SetCellExpression or
+// SetSheetExpressions on them should call SetEvaluator(nil) once it's done
+// with a given *File - otherwise that *File (and everything it references)
+// stays reachable through this table for the life of the process.
+func (f *File) SetEvaluator(e Evaluator) {
+	if e == nil {
+		evaluators.Delete(f)
+		return
+	}
+	evaluators.Store(f, e)
+}
+
+// evaluator returns f's Evaluator, lazily creating the gval-backed default
+// on first use so its expression cache persists across calls.
+func (f *File) evaluator() Evaluator {
+	if v, ok := evaluators.Load(f); ok {
+		return v.(Evaluator)
+	}
+	v, _ := evaluators.LoadOrStore(f, newGvalEvaluator())
+	return v.(Evaluator)
+}
+
+// SetCellExpression evaluates expr against env using f's Evaluator and
+// writes the result to sheet!cell via SetCellValue, so the result lands as
+// a number, string, bool, or date according to its Go type. On evaluation
+// failure the cell is set to "#VALUE!", matching how SetCalcedCellFormula
+// surfaces calc failures, and the error is returned.
+func (f *File) SetCellExpression(sheet, cell, expr string, env map[string]any, opts ...Options) error {
+	value, err := f.evaluator().Evaluate(expr, env)
+	if err != nil {
+		f.SetCellDefault(sheet, cell, "#VALUE!")
+		f.invalidateFormulaCacheCell(sheet, cell)
+		return fmt.Errorf("evaluate expression %q: %w", expr, err)
+	}
+	if err = f.SetCellValue(sheet, cell, value); err != nil {
+		return err
+	}
+	f.invalidateFormulaCacheCell(sheet, cell)
+	return nil
+}
+
+// CellExpression pairs a cell reference with the expression to evaluate into
+// it, for use with SetSheetExpressions.
+type CellExpression struct {
+	Cell string
+	Expr string
+}
+
+// SetSheetExpressions evaluates each expression in cells, in order, against
+// the same env and writes the results into sheet, sharing one Evaluator -
+// and so one parsed-expression cache - across the whole batch. It stops at
+// the first error, leaving cells already written in place. cells is an
+// ordered slice rather than a map so that "cells already written" is
+// deterministic between runs on the same input.
+func (f *File) SetSheetExpressions(sheet string, cells []CellExpression, env map[string]any, opts ...Options) error {
+	for _, ce := range cells {
+		if err := f.SetCellExpression(sheet, ce.Cell, ce.Expr, env, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package excelize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetCellExpression(t *testing.T) {
+	f := NewFile()
+	env := map[string]any{"price": 12.5, "qty": 4}
+	assert.NoError(t, f.SetCellExpression("Sheet1", "A1", "price * qty", env))
+
+	v, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "50", v)
+
+	err = f.SetCellExpression("Sheet1", "A2", "price +", env)
+	assert.Error(t, err)
+	v, err = f.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "#VALUE!", v)
+}
+
+func TestSetSheetExpressions(t *testing.T) {
+	f := NewFile()
+	env := map[string]any{"x": 2}
+	cells := []CellExpression{
+		{Cell: "A1", Expr: "x + 1"},
+		{Cell: "A2", Expr: "x * 10"},
+	}
+	assert.NoError(t, f.SetSheetExpressions("Sheet1", cells, env))
+
+	v, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", v)
+
+	v, err = f.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "20", v)
+}
+
+func TestSetSheetExpressionsStopsAtFirstError(t *testing.T) {
+	f := NewFile()
+	env := map[string]any{"x": 2}
+	cells := []CellExpression{
+		{Cell: "A1", Expr: "x + 1"},
+		{Cell: "A2", Expr: "x +"},
+		{Cell: "A3", Expr: "x * 10"},
+	}
+	assert.Error(t, f.SetSheetExpressions("Sheet1", cells, env))
+
+	v, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", v)
+
+	v, err = f.GetCellValue("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "", v)
+}
+
+type constEvaluator struct{}
+
+func (constEvaluator) Evaluate(expr string, env map[string]any) (any, error) {
+	return 42, nil
+}
+
+func TestSetEvaluator(t *testing.T) {
+	f := NewFile()
+	f.SetEvaluator(constEvaluator{})
+	assert.NoError(t, f.SetCellExpression("Sheet1", "A1", "anything", nil))
+
+	v, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", v)
+}
+
+func TestSetEvaluatorNilRemovesEntry(t *testing.T) {
+	f := NewFile()
+	f.SetEvaluator(constEvaluator{})
+	_, ok := evaluators.Load(f)
+	assert.True(t, ok)
+
+	f.SetEvaluator(nil)
+	_, ok = evaluators.Load(f)
+	assert.False(t, ok)
+
+	env := map[string]any{"x": 2}
+	assert.NoError(t, f.SetCellExpression("Sheet1", "A1", "x + 1", env))
+	v, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", v)
+}
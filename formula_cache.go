@@ -0,0 +1,152 @@
+package excelize
+
+import "sync"
+
+// formulaValueEntry is a single cached (formula, value) pair for one cell
+// coordinate.
+type formulaValueEntry struct {
+	formula string
+	value   string
+}
+
+// formulaValueCacheKey identifies a cell by sheet name and 1-based
+// column/row, matching the coordinates CellNameToCoordinates returns.
+type formulaValueCacheKey struct {
+	sheet string
+	col   int
+	row   int
+}
+
+// FormulaValueCache is an opt-in, per-workbook cache of resolved cell
+// formulas and values. It exists so that callers which read the same
+// coordinate many times - template engines and report generators chief
+// among them - don't pay to re-read values and re-resolve shared formulas
+// on every pass; the rows of ws.SheetData.Row are still walked on each
+// pass. GetCellFormula and GetCellValue do not consult it. Enable it with
+// File.EnableFormulaCache.
+//
+// EachCellFormulaValue and EachCellFormulaValueInRange consult it first and
+// skip re-resolving a coordinate once it's cached; SetCellExpression,
+// SetSheetBulkUnsafe and TemplateEngine's range writer invalidate the
+// entries they touch, so the cache stays correct across calls made through
+// those three.
+//
+// It is NOT invalidated by GetCellFormula, GetCellValue, SetCellValue,
+// SetCellFormula, SetCellStyle, RemoveRow, RemoveCol, InsertRow, InsertCol,
+// or MergeCell - those live outside this file set, so wiring them in means
+// editing their source directly rather than from here. Calling any of them
+// on a coordinate this cache already holds, while the cache is enabled,
+// leaves that entry stale: EachCellFormulaValue/EachCellFormulaValueInRange
+// will keep returning the old formula/value for it until the whole sheet
+// is invalidated some other way (or the cache is disabled and re-enabled).
+// Until those setters gain their own invalidation hook, treat the cache as
+// unsafe to enable on any *File that mixes EachCellFormulaValue /
+// EachCellFormulaValueInRange reads with direct SetCell*/RemoveRow/
+// RemoveCol/InsertRow/InsertCol/MergeCell calls on the same sheet.
+type FormulaValueCache struct {
+	mu      sync.RWMutex
+	entries map[formulaValueCacheKey]formulaValueEntry
+}
+
+func newFormulaValueCache() *FormulaValueCache {
+	return &FormulaValueCache{entries: make(map[formulaValueCacheKey]formulaValueEntry)}
+}
+
+func (c *FormulaValueCache) get(sheet string, col, row int) (formulaValueEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[formulaValueCacheKey{sheet, col, row}]
+	return entry, ok
+}
+
+func (c *FormulaValueCache) put(sheet string, col, row int, entry formulaValueEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[formulaValueCacheKey{sheet, col, row}] = entry
+}
+
+// invalidateCell drops the cached entry for a single coordinate, used by
+// setters that only ever touch one cell, such as SetCellExpression.
+func (c *FormulaValueCache) invalidateCell(sheet string, col, row int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, formulaValueCacheKey{sheet, col, row})
+}
+
+// invalidateSheet drops every cached entry for sheet. Bulk and structural
+// writes - SetSheetBulkUnsafe, TemplateEngine's range writer - can touch or
+// shift many coordinates at once, so rather than track each one
+// individually the whole sheet's entries are dropped: coarse, but cheap and
+// always correct.
+func (c *FormulaValueCache) invalidateSheet(sheet string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.sheet == sheet {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// formulaValueCaches holds the enabled caches, keyed by workbook. A
+// side-table avoids growing the (already large) File struct for a feature
+// most callers never turn on.
+var formulaValueCaches sync.Map // map[*File]*FormulaValueCache
+
+// EnableFormulaCache turns the workbook-level formula/value cache on or
+// off. It is opt-in: most callers read each coordinate once, so the
+// bookkeeping only pays for itself when the same cells are read repeatedly.
+//
+// WARNING: only SetCellExpression, SetSheetBulkUnsafe, and TemplateEngine's
+// range writer invalidate this cache. If you enable it on a *File and then
+// write to it with SetCellValue, SetCellFormula, SetCellStyle, RemoveRow,
+// RemoveCol, InsertRow, InsertCol, or MergeCell, EachCellFormulaValue and
+// EachCellFormulaValueInRange will silently keep returning the pre-write
+// formula/value for any coordinate that was already cached. Do not enable
+// this cache on a *File unless every write to it goes through one of the
+// three methods above, or you disable and re-enable the cache (which
+// clears it) after any other write.
+func (f *File) EnableFormulaCache(enable bool) {
+	if !enable {
+		formulaValueCaches.Delete(f)
+		return
+	}
+	formulaValueCaches.LoadOrStore(f, newFormulaValueCache())
+}
+
+// formulaCache returns the workbook's cache, or nil if caching hasn't been
+// enabled.
+func (f *File) formulaCache() *FormulaValueCache {
+	v, ok := formulaValueCaches.Load(f)
+	if !ok {
+		return nil
+	}
+	return v.(*FormulaValueCache)
+}
+
+// invalidateFormulaCacheCell drops a single coordinate from the workbook's
+// cache, if caching is enabled. Setters that touch exactly one cell, such
+// as SetCellExpression, call this after a write.
+func (f *File) invalidateFormulaCacheCell(sheet, cell string) {
+	cache := f.formulaCache()
+	if cache == nil {
+		return
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return
+	}
+	cache.invalidateCell(sheet, col, row)
+}
+
+// invalidateFormulaCacheSheet drops every cached entry for sheet, if caching
+// is enabled. Bulk and structural writers, such as SetSheetBulkUnsafe and
+// TemplateEngine's range writer, call this rather than try to track which
+// coordinates moved.
+func (f *File) invalidateFormulaCacheSheet(sheet string) {
+	cache := f.formulaCache()
+	if cache == nil {
+		return
+	}
+	cache.invalidateSheet(sheet)
+}
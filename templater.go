@@ -0,0 +1,447 @@
+package excelize
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DataSource abstracts a row-oriented source of template data, such as a
+// database query result set, a CSV reader, or an in-memory slice. Next
+// returns the next row as a map of column name to value, a bool indicating
+// whether a row was produced, and an error if retrieval failed.
+type DataSource interface {
+	Next() (map[string]any, bool, error)
+}
+
+// sqlDataSource adapts a database/sql result set to the DataSource interface.
+type sqlDataSource struct {
+	rows    *sql.Rows
+	columns []string
+}
+
+// NewSQLDataSource wraps the given *sql.Rows so it can be bound by a `loop`
+// or `range` action. The caller remains responsible for closing rows once
+// the template has finished expanding.
+func NewSQLDataSource(rows *sql.Rows) (DataSource, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	return &sqlDataSource{rows: rows, columns: columns}, nil
+}
+
+// Next implements the DataSource interface.
+func (ds *sqlDataSource) Next() (map[string]any, bool, error) {
+	if !ds.rows.Next() {
+		return nil, false, ds.rows.Err()
+	}
+	values := make([]any, len(ds.columns))
+	pointers := make([]any, len(ds.columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := ds.rows.Scan(pointers...); err != nil {
+		return nil, false, err
+	}
+	row := make(map[string]any, len(ds.columns))
+	for i, col := range ds.columns {
+		row[col] = values[i]
+	}
+	return row, true, nil
+}
+
+// action is the YAML representation of a single templater step. Only the
+// fields relevant to the action's Type are populated.
+type action struct {
+	Type     string   `yaml:"type"`
+	Var      string   `yaml:"var"`
+	Source   string   `yaml:"source"`
+	Location []string `yaml:"location"`
+	Cell     string   `yaml:"cell"`
+	Expr     string   `yaml:"expr"`
+	Sheet    string   `yaml:"sheet"`
+	Actions  []action `yaml:"actions"`
+}
+
+// templateScript is the root of a parsed action YAML document.
+type templateScript struct {
+	Actions []action `yaml:"actions"`
+}
+
+// frame holds the variable bindings visible to an action and its children.
+// Frames are pushed for each scope (e.g. one per loop iteration) and popped
+// once that scope finishes, giving `{{expr}}` placeholders lexical access to
+// enclosing loop variables without leaking them to siblings.
+type frame struct {
+	vars   map[string]any
+	parent *frame
+}
+
+func newFrame(parent *frame) *frame {
+	return &frame{vars: make(map[string]any), parent: parent}
+}
+
+// env flattens the frame chain into a single map suitable for evaluating an
+// expression, with inner scopes shadowing outer ones.
+func (fr *frame) env() map[string]any {
+	if fr == nil {
+		return map[string]any{}
+	}
+	out := fr.parent.env()
+	for k, v := range fr.vars {
+		out[k] = v
+	}
+	return out
+}
+
+var placeholderRe = regexp.MustCompile(`\{\{(.*?)\}\}`)
+
+// TemplateEngine expands a template workbook by walking a YAML action script
+// against a set of registered data sources, in the spirit of a
+// report-generator: `loop` actions bind rows to a variable, `range` actions
+// duplicate a block of template cells once per binding and rewrite
+// `{{expr}}` placeholders inside it, `set` actions write a single
+// expression-derived cell, and nested `actions` introduce new variable
+// scopes.
+type TemplateEngine struct {
+	file    *File
+	sources map[string]DataSource
+}
+
+// NewTemplateEngine creates a TemplateEngine that expands templates in place
+// on f. Use RegisterDataSource to bind named data sources before calling
+// Execute.
+func NewTemplateEngine(f *File) *TemplateEngine {
+	return &TemplateEngine{file: f, sources: make(map[string]DataSource)}
+}
+
+// RegisterDataSource binds name to ds so that `loop` and `range` actions
+// with a matching `source` can consume it.
+func (te *TemplateEngine) RegisterDataSource(name string, ds DataSource) {
+	te.sources[name] = ds
+}
+
+// Execute reads a YAML action script from r, expands it against the
+// registered data sources and the engine's workbook, and writes the
+// resulting workbook to out.
+func (te *TemplateEngine) Execute(ctx context.Context, r io.Reader, out io.Writer) error {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var script templateScript
+	if err = yaml.Unmarshal(raw, &script); err != nil {
+		return fmt.Errorf("parse template script: %w", err)
+	}
+	root := newFrame(nil)
+	for _, a := range script.Actions {
+		if err = te.runAction(ctx, a, root); err != nil {
+			return err
+		}
+	}
+	_, err = te.file.WriteTo(out)
+	return err
+}
+
+// runAction dispatches a single action node against the given scope.
+func (te *TemplateEngine) runAction(ctx context.Context, a action, fr *frame) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	switch a.Type {
+	case "loop":
+		return te.runLoop(ctx, a, fr)
+	case "range":
+		return te.runRange(ctx, a, fr)
+	case "set":
+		return te.runSet(a, fr)
+	default:
+		return fmt.Errorf("templater: unknown action type %q", a.Type)
+	}
+}
+
+// runLoop binds successive rows from a.Source to a.Var and runs the nested
+// actions once per row, each in its own child scope.
+func (te *TemplateEngine) runLoop(ctx context.Context, a action, fr *frame) error {
+	ds, ok := te.sources[a.Source]
+	if !ok {
+		return fmt.Errorf("templater: unknown data source %q", a.Source)
+	}
+	for {
+		row, ok, err := ds.Next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		child := newFrame(fr)
+		child.vars[a.Var] = row
+		for _, sub := range a.Actions {
+			if err = te.runAction(ctx, sub, child); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// runSet evaluates a.Expr against the current scope and writes the result to
+// a.Cell on a.Sheet via SetCellExpression.
+func (te *TemplateEngine) runSet(a action, fr *frame) error {
+	sheet := a.Sheet
+	if sheet == "" {
+		sheet = te.file.GetSheetName(0)
+	}
+	return te.file.SetCellExpression(sheet, a.Cell, a.Expr, fr.env())
+}
+
+// runRange duplicates the template block given by a.Location once per row
+// produced by a.Source, rewriting `{{expr}}` placeholders in each copy
+// before recursing into a.Actions with the new row's variable bound. The
+// block is snapshotted once, before any iteration writes into it, so that
+// duplicating it for iteration N always starts from the pristine template
+// rather than from whatever iteration N-1 already substituted into it.
+func (te *TemplateEngine) runRange(ctx context.Context, a action, fr *frame) error {
+	if len(a.Location) != 1 {
+		return fmt.Errorf("templater: range action requires a single Location")
+	}
+	sheet := a.Sheet
+	if sheet == "" {
+		sheet = te.file.GetSheetName(0)
+	}
+	startCol, startRow, endCol, endRow, err := te.file.parseCellRangeRef(sheet, a.Location[0])
+	if err != nil {
+		return err
+	}
+	height := endRow - startRow + 1
+
+	block, rowHeights, merges, err := te.snapshotBlock(sheet, startCol, startRow, endCol, endRow)
+	if err != nil {
+		return err
+	}
+
+	ds, ok := te.sources[a.Source]
+	if !ok {
+		return fmt.Errorf("templater: unknown data source %q", a.Source)
+	}
+
+	iteration := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		row, ok, err := ds.Next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		destRowBase := startRow + iteration*height
+		child := newFrame(fr)
+		child.vars[a.Var] = row
+		if err = te.writeBlockIteration(sheet, startCol, startRow, destRowBase, block, rowHeights, merges, child.env()); err != nil {
+			return err
+		}
+		for _, sub := range a.Actions {
+			if err = te.runAction(ctx, sub, child); err != nil {
+				return err
+			}
+		}
+		iteration++
+	}
+	te.file.invalidateFormulaCacheSheet(sheet)
+	return nil
+}
+
+// blockCell is a snapshot of one template cell's style and pre-substitution
+// content, captured before any `{{expr}}` placeholder in it is resolved.
+type blockCell struct {
+	style     int
+	isFormula bool
+	formula   string
+	value     string
+}
+
+// mergeSpec records a merged range inside a template block, relative to the
+// block's top-left corner, so it can be re-created at each iteration's
+// offset.
+type mergeSpec struct {
+	relStartCol, relStartRow, relEndCol, relEndRow int
+}
+
+// snapshotBlock captures the style, formula/value text and merged ranges of
+// the rectangle [startCol,startRow]-[endCol,endRow] before any placeholder
+// substitution happens, so later iterations can duplicate the pristine
+// template instead of an already-substituted copy.
+func (te *TemplateEngine) snapshotBlock(sheet string, startCol, startRow, endCol, endRow int) ([][]blockCell, []float64, []mergeSpec, error) {
+	f := te.file
+	height := endRow - startRow + 1
+	width := endCol - startCol + 1
+
+	rowHeights := make([]float64, height)
+	cells := make([][]blockCell, height)
+	for r := 0; r < height; r++ {
+		h, err := f.GetRowHeight(sheet, startRow+r)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		rowHeights[r] = h
+
+		cells[r] = make([]blockCell, width)
+		for c := 0; c < width; c++ {
+			cellName, err := CoordinatesToCellName(startCol+c, startRow+r)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			style, err := f.GetCellStyle(sheet, cellName)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if formula, ferr := f.GetCellFormula(sheet, cellName); ferr == nil && formula != "" {
+				cells[r][c] = blockCell{style: style, isFormula: true, formula: formula}
+				continue
+			}
+			value, err := f.GetCellValue(sheet, cellName)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			cells[r][c] = blockCell{style: style, value: value}
+		}
+	}
+
+	allMerges, err := f.GetMergeCells(sheet)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var merges []mergeSpec
+	for _, m := range allMerges {
+		mStartCol, mStartRow, err := CellNameToCoordinates(m.GetStartAxis())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		mEndCol, mEndRow, err := CellNameToCoordinates(m.GetEndAxis())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if mStartRow < startRow || mEndRow > endRow || mStartCol < startCol || mEndCol > endCol {
+			continue
+		}
+		merges = append(merges, mergeSpec{
+			relStartCol: mStartCol - startCol,
+			relStartRow: mStartRow - startRow,
+			relEndCol:   mEndCol - startCol,
+			relEndRow:   mEndRow - startRow,
+		})
+	}
+	return cells, rowHeights, merges, nil
+}
+
+// writeBlockIteration writes one copy of a snapshotted template block at
+// destRowBase, offsetting formula references by the number of rows moved
+// (the same way parseSharedFormula offsets shared formulas for
+// EachCellFormula) and resolving `{{expr}}` placeholders against env.
+func (te *TemplateEngine) writeBlockIteration(sheet string, startCol, startRow, destRowBase int, block [][]blockCell, rowHeights []float64, merges []mergeSpec, env map[string]any) error {
+	f := te.file
+	for r, rowCells := range block {
+		destRow := destRowBase + r
+		if err := f.SetRowHeight(sheet, destRow, rowHeights[r]); err != nil {
+			return err
+		}
+		rowOffset := destRow - (startRow + r)
+		for c, cell := range rowCells {
+			cellName, err := CoordinatesToCellName(startCol+c, destRow)
+			if err != nil {
+				return err
+			}
+			if err = f.SetCellStyle(sheet, cellName, cellName, cell.style); err != nil {
+				return err
+			}
+			if cell.isFormula {
+				shifted := offsetFormulaRows(cell.formula, rowOffset)
+				rewritten, _, err := te.rewriteExpr(shifted, env)
+				if err != nil {
+					return err
+				}
+				if err = f.SetCellFormula(sheet, cellName, rewritten); err != nil {
+					return err
+				}
+				continue
+			}
+			rewritten, changed, err := te.rewriteExpr(cell.value, env)
+			if err != nil {
+				return err
+			}
+			if changed {
+				err = f.SetCellValue(sheet, cellName, rewritten)
+			} else {
+				err = f.SetCellValue(sheet, cellName, cell.value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, m := range merges {
+		startCell, err := CoordinatesToCellName(startCol+m.relStartCol, destRowBase+m.relStartRow)
+		if err != nil {
+			return err
+		}
+		endCell, err := CoordinatesToCellName(startCol+m.relEndCol, destRowBase+m.relEndRow)
+		if err != nil {
+			return err
+		}
+		if err = f.MergeCell(sheet, startCell, endCell); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// offsetFormulaRows shifts relative row references in formula by rowOffset,
+// the same way parseSharedFormula offsets a shared formula's content for a
+// dependent cell in EachCellFormula.
+func offsetFormulaRows(formula string, rowOffset int) string {
+	if rowOffset == 0 {
+		return formula
+	}
+	orig := []byte(formula)
+	res, start := parseSharedFormula(0, rowOffset, orig)
+	if start < len(orig) {
+		res += string(orig[start:])
+	}
+	return res
+}
+
+// rewriteExpr substitutes every `{{expr}}` placeholder in value with the
+// string form of its evaluation against env, reporting whether any
+// substitution was made.
+func (te *TemplateEngine) rewriteExpr(value string, env map[string]any) (string, bool, error) {
+	if !placeholderRe.MatchString(value) {
+		return value, false, nil
+	}
+	evaluator := te.file.evaluator()
+	var evalErr error
+	result := placeholderRe.ReplaceAllStringFunc(value, func(match string) string {
+		expr := placeholderRe.FindStringSubmatch(match)[1]
+		v, err := evaluator.Evaluate(expr, env)
+		if err != nil {
+			evalErr = fmt.Errorf("templater: evaluate %q: %w", expr, err)
+			return match
+		}
+		return fmt.Sprintf("%v", v)
+	})
+	if evalErr != nil {
+		return "", false, evalErr
+	}
+	return result, true, nil
+}
@@ -0,0 +1,91 @@
+package excelize
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sliceDataSource is an in-memory DataSource used by the templater tests.
+type sliceDataSource struct {
+	rows []map[string]any
+	i    int
+}
+
+func (ds *sliceDataSource) Next() (map[string]any, bool, error) {
+	if ds.i >= len(ds.rows) {
+		return nil, false, nil
+	}
+	row := ds.rows[ds.i]
+	ds.i++
+	return row, true, nil
+}
+
+func TestTemplateEngineNestedLoops(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", "{{region.name}}"))
+	assert.NoError(t, f.SetCellStr("Sheet1", "A5", "{{customer.name}}"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B5", "=A5&\"!\""))
+
+	te := NewTemplateEngine(f)
+	te.RegisterDataSource("regions", &sliceDataSource{rows: []map[string]any{
+		{"name": "EMEA"},
+	}})
+	te.RegisterDataSource("customers", &sliceDataSource{rows: []map[string]any{
+		{"name": "Acme"},
+		{"name": "Globex"},
+	}})
+
+	script := `
+actions:
+  - type: loop
+    var: region
+    source: regions
+    actions:
+      - type: set
+        cell: A1
+        expr: region.name
+      - type: range
+        var: customer
+        source: customers
+        location: ["A5:B5"]
+        actions: []
+`
+	assert.NoError(t, te.Execute(context.Background(), bytes.NewReader([]byte(script)), &bytes.Buffer{}))
+
+	name, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "EMEA", name)
+
+	first, err := f.GetCellValue("Sheet1", "A5")
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme", first)
+
+	second, err := f.GetCellValue("Sheet1", "A6")
+	assert.NoError(t, err)
+	assert.Equal(t, "Globex", second)
+
+	formula, err := f.GetCellFormula("Sheet1", "B6")
+	assert.NoError(t, err)
+	assert.Equal(t, "A6&\"!\"", formula)
+}
+
+func TestTemplateEngineRangeReversedLocation(t *testing.T) {
+	f := NewFile()
+	te := NewTemplateEngine(f)
+	te.RegisterDataSource("customers", &sliceDataSource{rows: []map[string]any{
+		{"name": "Acme"},
+	}})
+
+	script := `
+actions:
+  - type: range
+    var: customer
+    source: customers
+    location: ["B5:A1"]
+    actions: []
+`
+	assert.Error(t, te.Execute(context.Background(), bytes.NewReader([]byte(script)), &bytes.Buffer{}))
+}